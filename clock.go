@@ -0,0 +1,32 @@
+package timedcache
+
+
+import (
+    "time"
+)
+
+
+// Clock is the source of the current time used throughout the cache
+// It exists so tests can inject a fake clock instead of sleeping on the
+// real one; production code never needs to supply its own
+type Clock interface {
+    Now() (time.Time)
+}
+
+
+// realClock is the default Clock, backed by time.Now
+type realClock struct{}
+
+
+func (realClock) Now() (time.Time) {
+    return time.Now().UTC()
+}
+
+
+// SetClock overrides the cache's time source
+// Intended for tests; production callers should leave the default realClock
+func (tc *timedCache[K, V]) SetClock(clock Clock) {
+    tc.mu.Lock()
+    tc.clock = clock
+    tc.mu.Unlock()
+}
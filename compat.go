@@ -0,0 +1,74 @@
+package timedcache
+
+
+import (
+    "time"
+    "errors"
+)
+
+
+// StringCache is a thin shim over TimedCache[string, interface{}] kept for
+// callers migrating from the pre-generics API, where keys were always
+// strings and values were stored as interface{}.
+// Note for migrating callers: New() and NewWithCustomInterval() now return
+// *TimedCache[K, V] (a pointer) instead of the old value type TimedCache;
+// use StringCache's constructors below if you need the old interface{}
+// based signatures without touching call sites.
+type StringCache struct {
+    tc *TimedCache[string, interface{}]
+}
+
+
+// Creates a new instance of StringCache
+func NewStringCache() (*StringCache) {
+    return &StringCache{tc: New[string, interface{}]()}
+}
+
+
+// Creates a new instance of StringCache with a custom interval
+// interval - the custom interval for expiration checking
+func NewStringCacheWithCustomInterval(interval time.Duration) (*StringCache) {
+    return &StringCache{tc: NewWithCustomInterval[string, interface{}](interval)}
+}
+
+
+// Add item without refreshing
+func (sc *StringCache) Put(key string, content interface{}, duration time.Duration) {
+    sc.tc.Put(key, content, duration)
+}
+
+
+// Add item with refreshing
+func (sc *StringCache) PutRefreshing(key string, content interface{}, duration time.Duration) {
+    sc.tc.PutRefreshing(key, content, duration)
+}
+
+
+// Delete item
+func (sc *StringCache) Delete(key string) {
+    sc.tc.Delete(key)
+}
+
+
+// Get item
+// If item is automatically refreshable, extends the expiration time
+// Kept the old interface{}/error signature rather than the generic
+// (V, bool) one so existing callers don't need to change.
+func (sc *StringCache) Get(key string) (interface{}, error) {
+    if content, ok := sc.tc.Get(key); ok {
+        return content, nil
+    }
+    return nil, errors.New("Item not found")
+}
+
+
+// Checks whether a key exists in the repository
+func (sc *StringCache) KeyExists(key string) (bool) {
+    return sc.tc.KeyExists(key)
+}
+
+
+// Remove expired
+func (sc *StringCache) RemoveExpired() {
+    sc.tc.RemoveExpired()
+}
@@ -0,0 +1,65 @@
+package timedcache_test
+
+
+import (
+    "testing"
+    "time"
+
+    timedcache "github.com/TomiHiltunen/TimedCache"
+)
+
+
+func TestStringCachePutGetDelete(t *testing.T) {
+    cache := timedcache.NewStringCache()
+
+    cache.Put("k", "v", time.Minute)
+    v, err := cache.Get("k")
+    if err != nil || v != "v" {
+        t.Fatalf("got (%v, %v), want (\"v\", nil)", v, err)
+    }
+
+    cache.Delete("k")
+    if _, err := cache.Get("k"); err == nil {
+        t.Fatal("expected an error after deleting the key")
+    }
+}
+
+
+func TestStringCacheGetMissingKeyErrors(t *testing.T) {
+    cache := timedcache.NewStringCache()
+
+    if _, err := cache.Get("missing"); err == nil {
+        t.Fatal("expected an error for a key that was never put")
+    }
+}
+
+
+func TestStringCachePutRefreshingAndKeyExists(t *testing.T) {
+    cache := timedcache.NewStringCacheWithCustomInterval(time.Hour)
+
+    if cache.KeyExists("k") {
+        t.Fatal("expected KeyExists to be false before Put")
+    }
+
+    cache.PutRefreshing("k", 42, time.Minute)
+    if !cache.KeyExists("k") {
+        t.Fatal("expected KeyExists to be true after PutRefreshing")
+    }
+
+    v, err := cache.Get("k")
+    if err != nil || v != 42 {
+        t.Fatalf("got (%v, %v), want (42, nil)", v, err)
+    }
+}
+
+
+func TestStringCacheRemoveExpired(t *testing.T) {
+    cache := timedcache.NewStringCache()
+
+    cache.Put("k", "v", -time.Second) // already expired
+    cache.RemoveExpired()
+
+    if cache.KeyExists("k") {
+        t.Fatal("expected RemoveExpired to have dropped the already-expired key")
+    }
+}
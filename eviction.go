@@ -0,0 +1,26 @@
+package timedcache
+
+
+// EvictionReason describes why an item was removed from the cache
+type EvictionReason int
+
+
+const (
+    // Expired - the item's TTL ran out and it was swept by RemoveExpired (the
+    // janitor) or removed lazily by Get
+    Expired EvictionReason = iota
+    // Deleted - the item was removed via an explicit Delete call
+    Deleted
+    // Replaced - the item was overwritten by a new Put/PutRefreshing call
+    Replaced
+)
+
+
+// OnEvicted registers a callback invoked whenever an item leaves the cache
+// The callback runs outside the cache mutex, so it may safely call back
+// into the cache (e.g. Put/Delete) without deadlocking
+func (tc *timedCache[K, V]) OnEvicted(callback func(key K, value V, reason EvictionReason)) {
+    tc.mu.Lock()
+    tc.onEvicted = callback
+    tc.mu.Unlock()
+}
@@ -0,0 +1,105 @@
+package timedcache_test
+
+
+import (
+    "sync"
+    "testing"
+    "time"
+
+    timedcache "github.com/TomiHiltunen/TimedCache"
+)
+
+
+func TestOnEvictedFiresWithCorrectReason(t *testing.T) {
+    cache := timedcache.NewWithCustomInterval[string, int](time.Hour)
+    defer cache.Close()
+
+    clock := &fakeClock{now: time.Unix(1000, 0)}
+    cache.SetClock(clock)
+
+    var mu sync.Mutex
+    reasons := make(map[string]timedcache.EvictionReason)
+    cache.OnEvicted(func(key string, value int, reason timedcache.EvictionReason) {
+        mu.Lock()
+        reasons[key] = reason
+        mu.Unlock()
+    })
+
+    cache.Put("expires", 1, time.Second)
+    cache.Put("deleted", 2, time.Minute)
+    cache.Put("replaced", 3, time.Minute)
+
+    cache.Delete("deleted")
+    cache.Put("replaced", 30, time.Minute)
+
+    clock.now = clock.now.Add(2 * time.Second)
+    cache.Get("expires") // lazy expiry happens on Get, firing Expired
+
+    mu.Lock()
+    defer mu.Unlock()
+    if reasons["deleted"] != timedcache.Deleted {
+        t.Fatalf("got reason %v for deleted key, want Deleted", reasons["deleted"])
+    }
+    if reasons["replaced"] != timedcache.Replaced {
+        t.Fatalf("got reason %v for replaced key, want Replaced", reasons["replaced"])
+    }
+    if r, ok := reasons["expires"]; !ok || r != timedcache.Expired {
+        t.Fatalf("got reason (%v, fired=%v) for expired key, want Expired", r, ok)
+    }
+}
+
+
+// TestOnEvictedCanReenterCache confirms the callback runs outside the cache
+// mutex, as documented, by calling back into the cache from within it.
+func TestOnEvictedCanReenterCache(t *testing.T) {
+    cache := timedcache.New[string, int]()
+    defer cache.Close()
+
+    cache.OnEvicted(func(key string, value int, reason timedcache.EvictionReason) {
+        cache.Put("reentrant-"+key, value, time.Minute)
+    })
+
+    done := make(chan struct{})
+    go func() {
+        defer close(done)
+        cache.Put("a", 1, time.Minute)
+        cache.Delete("a")
+    }()
+
+    select {
+    case <-done:
+    case <-time.After(time.Second):
+        t.Fatal("OnEvicted callback deadlocked when calling back into the cache")
+    }
+
+    if _, ok := cache.Get("reentrant-a"); !ok {
+        t.Fatal("expected the reentrant Put made from OnEvicted to have taken effect")
+    }
+}
+
+
+func TestOnEvictedFiresExpiredFromRemoveExpired(t *testing.T) {
+    cache := timedcache.NewWithCustomInterval[string, int](time.Hour)
+    defer cache.Close()
+
+    clock := &fakeClock{now: time.Unix(1000, 0)}
+    cache.SetClock(clock)
+
+    var reason timedcache.EvictionReason
+    var fired bool
+    cache.OnEvicted(func(key string, value int, r timedcache.EvictionReason) {
+        fired = true
+        reason = r
+    })
+
+    cache.Put("k", 1, time.Second)
+    clock.now = clock.now.Add(2 * time.Second)
+    cache.RemoveExpired()
+
+    if !fired {
+        t.Fatal("expected OnEvicted to fire from RemoveExpired")
+    }
+    if reason != timedcache.Expired {
+        t.Fatalf("got reason %v from RemoveExpired, want Expired", reason)
+    }
+}
@@ -0,0 +1,49 @@
+package timedcache
+
+
+import (
+    "time"
+)
+
+
+// janitor periodically sweeps a timedCache for expired items
+// It is a standalone struct, referenced only by the goroutine that runs it
+// and by the timedCache that owns it, so that the exported *TimedCache
+// wrapper can still be garbage collected once the caller drops it
+type janitor struct {
+    interval  time.Duration
+    stopCh    chan struct{}
+}
+
+
+// startJanitor creates a janitor for tc and starts its sweep goroutine
+func startJanitor[K comparable, V any](tc *timedCache[K, V]) {
+    j := &janitor{
+        interval: tc.interval,
+        stopCh: make(chan struct{}),
+    }
+    tc.janitor = j
+    go j.run(tc.RemoveExpired)
+}
+
+
+// run sweeps on every tick until stop is called
+func (j *janitor) run(removeExpired func()) {
+    ticker := time.NewTicker(j.interval)
+    defer ticker.Stop()
+    for {
+        select {
+        case <-ticker.C:
+            removeExpired()
+        case <-j.stopCh:
+            return
+        }
+    }
+}
+
+
+// stop terminates the sweep goroutine
+// Must only be called once per janitor
+func (j *janitor) stop() {
+    close(j.stopCh)
+}
@@ -0,0 +1,79 @@
+package timedcache
+
+
+import (
+    "errors"
+    "fmt"
+    "sync"
+    "time"
+)
+
+
+// ErrNoLoader is returned by GetOrLoad when the key is missing and it is
+// called with a nil loader on a cache that has no default loader set via
+// NewWithLoader
+var ErrNoLoader = errors.New("timedcache: no loader given and no default loader set")
+
+
+// inflightCall tracks a loader call in progress so that concurrent
+// GetOrLoad calls for the same missing key wait on a single load
+// instead of each hitting the backend.
+type inflightCall[V any] struct {
+    wg      sync.WaitGroup
+    result  V
+    err     error
+}
+
+
+// Get item, loading it via loader on a miss
+// If loader is nil, the cache's default loader (set via NewWithLoader) is used
+// Concurrent calls for the same missing key share a single loader call
+// Errors from loader are returned but never cached
+// If loader panics, GetOrLoad recovers, reports the panic value as an error
+// to every caller waiting on this key, and clears the in-flight slot so a
+// later call for the same key is not left deadlocked behind it
+func (tc *timedCache[K, V]) GetOrLoad(key K, loader func() (V, time.Duration, error)) (content V, err error) {
+    if loader == nil {
+        loader = tc.loader
+    }
+
+    if content, ok := tc.Get(key); ok {
+        return content, nil
+    }
+
+    if loader == nil {
+        var zero V
+        return zero, ErrNoLoader
+    }
+
+    tc.inflightMu.Lock()
+    if call, ok := tc.inflight[key]; ok {
+        tc.inflightMu.Unlock()
+        call.wg.Wait()
+        return call.result, call.err
+    }
+
+    call := &inflightCall[V]{}
+    call.wg.Add(1)
+    tc.inflight[key] = call
+    tc.inflightMu.Unlock()
+
+    defer func() {
+        if r := recover(); r != nil {
+            err = fmt.Errorf("timedcache: loader panicked: %v", r)
+        }
+        tc.inflightMu.Lock()
+        delete(tc.inflight, key)
+        tc.inflightMu.Unlock()
+        call.result = content
+        call.err = err
+        call.wg.Done()
+    }()
+
+    var duration time.Duration
+    content, duration, err = loader()
+    if err == nil {
+        tc.Put(key, content, duration)
+    }
+    return content, err
+}
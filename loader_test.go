@@ -0,0 +1,115 @@
+package timedcache_test
+
+
+import (
+    "errors"
+    "sync"
+    "sync/atomic"
+    "testing"
+    "time"
+
+    timedcache "github.com/TomiHiltunen/TimedCache"
+)
+
+
+func TestGetOrLoadDedupesConcurrentMisses(t *testing.T) {
+    cache := timedcache.New[string, int]()
+    defer cache.Close()
+
+    var calls int32
+    var wg sync.WaitGroup
+    start := make(chan struct{})
+    const goroutines = 10
+    results := make([]int, goroutines)
+    errs := make([]error, goroutines)
+
+    for i := 0; i < goroutines; i++ {
+        wg.Add(1)
+        go func(i int) {
+            defer wg.Done()
+            <-start
+            results[i], errs[i] = cache.GetOrLoad("k", func() (int, time.Duration, error) {
+                atomic.AddInt32(&calls, 1)
+                time.Sleep(20 * time.Millisecond)
+                return 42, time.Minute, nil
+            })
+        }(i)
+    }
+    close(start)
+    wg.Wait()
+
+    if got := atomic.LoadInt32(&calls); got != 1 {
+        t.Fatalf("loader called %d times, want 1", got)
+    }
+    for i, v := range results {
+        if errs[i] != nil || v != 42 {
+            t.Fatalf("goroutine %d got (%d, %v), want (42, nil)", i, v, errs[i])
+        }
+    }
+}
+
+
+func TestGetOrLoadDoesNotCacheErrors(t *testing.T) {
+    cache := timedcache.New[string, int]()
+    defer cache.Close()
+
+    wantErr := errors.New("boom")
+    if _, err := cache.GetOrLoad("k", func() (int, time.Duration, error) {
+        return 0, 0, wantErr
+    }); err != wantErr {
+        t.Fatalf("got err %v, want %v", err, wantErr)
+    }
+    if _, ok := cache.Peek("k"); ok {
+        t.Fatal("an errored load should not have been cached")
+    }
+
+    v, err := cache.GetOrLoad("k", func() (int, time.Duration, error) {
+        return 7, time.Minute, nil
+    })
+    if err != nil || v != 7 {
+        t.Fatalf("got (%d, %v), want (7, nil)", v, err)
+    }
+}
+
+
+func TestGetOrLoadWithoutALoaderErrors(t *testing.T) {
+    cache := timedcache.New[string, int]()
+    defer cache.Close()
+
+    if _, err := cache.GetOrLoad("k", nil); err != timedcache.ErrNoLoader {
+        t.Fatalf("got err %v, want ErrNoLoader", err)
+    }
+}
+
+
+// TestGetOrLoadRecoversFromPanickingLoader guards against a panicking
+// loader leaking its inflight slot: before the fix, a panic skipped the
+// delete(tc.inflight, key)/wg.Done() cleanup and every later GetOrLoad for
+// the same key blocked forever.
+func TestGetOrLoadRecoversFromPanickingLoader(t *testing.T) {
+    cache := timedcache.New[string, int]()
+    defer cache.Close()
+
+    if _, err := cache.GetOrLoad("k", func() (int, time.Duration, error) {
+        panic("boom")
+    }); err == nil {
+        t.Fatal("expected an error from a panicking loader, got nil")
+    }
+
+    done := make(chan struct{})
+    go func() {
+        defer close(done)
+        v, err := cache.GetOrLoad("k", func() (int, time.Duration, error) {
+            return 7, time.Minute, nil
+        })
+        if err != nil || v != 7 {
+            t.Errorf("got (%d, %v), want (7, nil)", v, err)
+        }
+    }()
+
+    select {
+    case <-done:
+    case <-time.After(time.Second):
+        t.Fatal("GetOrLoad deadlocked on a key left behind by a panicking loader")
+    }
+}
@@ -0,0 +1,74 @@
+package timedcache
+
+
+import (
+    "encoding/gob"
+    "io"
+    "os"
+)
+
+
+// Save writes the still-valid items in the cache to w using encoding/gob
+// Already-expired items are skipped
+// If V is an interface type, callers storing concrete types through it must
+// gob.Register those types beforehand, same as with encoding/gob generally
+func (tc *timedCache[K, V]) Save(w io.Writer) (error) {
+    now := tc.currentClock().Now()
+    toSave := make(map[K]cacheItem[V])
+    for _, sh := range tc.shards {
+        sh.mu.RLock()
+        for key, item := range sh.items {
+            if !tc.expired(key, item, now) {
+                toSave[key] = item
+            }
+        }
+        sh.mu.RUnlock()
+    }
+    return gob.NewEncoder(w).Encode(toSave)
+}
+
+
+// SaveFile writes the cache to the file at path, creating or truncating it
+func (tc *timedCache[K, V]) SaveFile(path string) (error) {
+    f, err := os.Create(path)
+    if err != nil {
+        return err
+    }
+    defer f.Close()
+    return tc.Save(f)
+}
+
+
+// Load reads items previously written by Save from r and merges them into
+// the cache, overwriting any existing entries with the same key
+// Items that have expired by the time they are loaded are dropped;
+// items still valid retain their original ExpiresAt, and therefore their
+// remaining TTL
+func (tc *timedCache[K, V]) Load(r io.Reader) (error) {
+    loaded := make(map[K]cacheItem[V])
+    if err := gob.NewDecoder(r).Decode(&loaded); err != nil {
+        return err
+    }
+    now := tc.currentClock().Now()
+    for key, item := range loaded {
+        if tc.expired(key, item, now) {
+            continue
+        }
+        sh := tc.shardFor(key)
+        sh.mu.Lock()
+        sh.items[key] = item
+        sh.mu.Unlock()
+    }
+    return nil
+}
+
+
+// LoadFile reads and merges the cache previously written to the file at path
+func (tc *timedCache[K, V]) LoadFile(path string) (error) {
+    f, err := os.Open(path)
+    if err != nil {
+        return err
+    }
+    defer f.Close()
+    return tc.Load(f)
+}
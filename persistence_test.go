@@ -0,0 +1,101 @@
+package timedcache_test
+
+
+import (
+    "bytes"
+    "os"
+    "path/filepath"
+    "testing"
+    "time"
+
+    timedcache "github.com/TomiHiltunen/TimedCache"
+)
+
+
+func TestSaveLoadDropsExpiredAndKeepsRemainingTTL(t *testing.T) {
+    src := timedcache.NewWithCustomInterval[string, int](time.Hour)
+    defer src.Close()
+
+    clock := &fakeClock{now: time.Unix(1000, 0)}
+    src.SetClock(clock)
+
+    src.Put("expired", 1, time.Second)
+    src.Put("valid", 2, time.Minute)
+    clock.now = clock.now.Add(2 * time.Second)
+
+    var buf bytes.Buffer
+    if err := src.Save(&buf); err != nil {
+        t.Fatalf("Save failed: %v", err)
+    }
+
+    dst := timedcache.NewWithCustomInterval[string, int](time.Hour)
+    defer dst.Close()
+    dst.SetClock(clock)
+
+    if err := dst.Load(&buf); err != nil {
+        t.Fatalf("Load failed: %v", err)
+    }
+
+    if _, ok := dst.Get("expired"); ok {
+        t.Fatal("expected an already-expired item to have been dropped by Save")
+    }
+    v, ok := dst.Get("valid")
+    if !ok || v != 2 {
+        t.Fatalf("got (%d, %v), want (2, true) for still-valid item", v, ok)
+    }
+}
+
+
+func TestLoadOverwritesExistingKey(t *testing.T) {
+    clock := &fakeClock{now: time.Unix(1000, 0)}
+
+    src := timedcache.NewWithCustomInterval[string, int](time.Hour)
+    defer src.Close()
+    src.SetClock(clock)
+    src.Put("k", 99, time.Minute)
+
+    var buf bytes.Buffer
+    if err := src.Save(&buf); err != nil {
+        t.Fatalf("Save failed: %v", err)
+    }
+
+    dst := timedcache.NewWithCustomInterval[string, int](time.Hour)
+    defer dst.Close()
+    dst.SetClock(clock)
+    dst.Put("k", 1, time.Minute)
+
+    if err := dst.Load(&buf); err != nil {
+        t.Fatalf("Load failed: %v", err)
+    }
+    if v, ok := dst.Get("k"); !ok || v != 99 {
+        t.Fatalf("got (%d, %v), want (99, true) after Load overwrote the existing key", v, ok)
+    }
+}
+
+
+func TestSaveFileLoadFileRoundTrip(t *testing.T) {
+    clock := &fakeClock{now: time.Unix(1000, 0)}
+
+    src := timedcache.NewWithCustomInterval[string, int](time.Hour)
+    defer src.Close()
+    src.SetClock(clock)
+    src.Put("k", 7, time.Minute)
+
+    path := filepath.Join(t.TempDir(), "cache.gob")
+    if err := src.SaveFile(path); err != nil {
+        t.Fatalf("SaveFile failed: %v", err)
+    }
+    if _, err := os.Stat(path); err != nil {
+        t.Fatalf("expected SaveFile to create %s: %v", path, err)
+    }
+
+    dst := timedcache.NewWithCustomInterval[string, int](time.Hour)
+    defer dst.Close()
+    dst.SetClock(clock)
+    if err := dst.LoadFile(path); err != nil {
+        t.Fatalf("LoadFile failed: %v", err)
+    }
+    if v, ok := dst.Get("k"); !ok || v != 7 {
+        t.Fatalf("got (%d, %v), want (7, true) after LoadFile", v, ok)
+    }
+}
@@ -0,0 +1,100 @@
+package timedcache
+
+
+import (
+    "time"
+)
+
+
+// Entry is the read-only view of a stored item handed to an
+// ExpirationPolicy, since cacheItem itself is unexported
+type Entry[V any] struct {
+    Value           V
+    ExpiresAt       time.Time
+    LastAccessedAt  time.Time
+    KeepRefreshing  bool
+    RefreshBy       time.Duration
+}
+
+
+// ExpirationPolicy decides whether a stored entry should be treated as
+// expired, mirroring the k8s client-go ExpirationCache design
+// Built-in policies are provided below; callers may also supply their own,
+// e.g. to expire based on an externally observed version or size
+type ExpirationPolicy[K comparable, V any] interface {
+    IsExpired(key K, entry Entry[V], now time.Time) (bool)
+}
+
+
+// SetExpirationPolicy overrides how the cache decides an item has expired
+// A nil policy (the default) restores the built-in ExpiresAt/KeepRefreshing
+// behavior driven by Put/PutRefreshing
+func (tc *timedCache[K, V]) SetExpirationPolicy(policy ExpirationPolicy[K, V]) {
+    tc.mu.Lock()
+    tc.policy = policy
+    tc.mu.Unlock()
+}
+
+
+// expired reports whether item should be treated as expired as of now,
+// honoring a custom ExpirationPolicy if one has been set
+func (tc *timedCache[K, V]) expired(key K, item cacheItem[V], now time.Time) (bool) {
+    tc.mu.RLock()
+    policy := tc.policy
+    tc.mu.RUnlock()
+    if policy != nil {
+        return policy.IsExpired(key, Entry[V] {
+            Value: item.Content,
+            ExpiresAt: item.ExpiresAt,
+            LastAccessedAt: item.LastAccessedAt,
+            KeepRefreshing: item.KeepRefreshing,
+            RefreshBy: item.RefreshBy,
+        }, now)
+    }
+    return item.isExpired(now)
+}
+
+
+// AbsoluteTTLPolicy expires an item strictly at ExpiresAt, ignoring any
+// per-item KeepRefreshing flag - the opposite of SlidingTTLPolicy
+type AbsoluteTTLPolicy[K comparable, V any] struct{}
+
+
+func (AbsoluteTTLPolicy[K, V]) IsExpired(_ K, entry Entry[V], now time.Time) (bool) {
+    return now.After(entry.ExpiresAt)
+}
+
+
+// SlidingTTLPolicy extends an item's life by RefreshBy every time it is
+// accessed, matching the package's existing PutRefreshing behavior
+type SlidingTTLPolicy[K comparable, V any] struct{}
+
+
+func (SlidingTTLPolicy[K, V]) IsExpired(_ K, entry Entry[V], now time.Time) (bool) {
+    if entry.KeepRefreshing {
+        return now.After(entry.LastAccessedAt.Add(entry.RefreshBy))
+    }
+    return now.After(entry.ExpiresAt)
+}
+
+
+// IdleSinceLastAccessPolicy expires an item once it has gone unused for
+// longer than MaxIdle, regardless of its ExpiresAt or KeepRefreshing value
+type IdleSinceLastAccessPolicy[K comparable, V any] struct {
+    MaxIdle  time.Duration
+}
+
+
+func (p IdleSinceLastAccessPolicy[K, V]) IsExpired(_ K, entry Entry[V], now time.Time) (bool) {
+    return now.After(entry.LastAccessedAt.Add(p.MaxIdle))
+}
+
+
+// NeverExpirePolicy disables expiration entirely; items are only removed
+// via an explicit Delete
+type NeverExpirePolicy[K comparable, V any] struct{}
+
+
+func (NeverExpirePolicy[K, V]) IsExpired(_ K, _ Entry[V], _ time.Time) (bool) {
+    return false
+}
@@ -0,0 +1,102 @@
+package timedcache_test
+
+
+import (
+    "testing"
+    "time"
+
+    timedcache "github.com/TomiHiltunen/TimedCache"
+)
+
+
+func TestNeverExpirePolicyIgnoresTTL(t *testing.T) {
+    cache := timedcache.New[string, int]()
+    defer cache.Close()
+
+    clock := &fakeClock{now: time.Unix(1000, 0)}
+    cache.SetClock(clock)
+    cache.SetExpirationPolicy(timedcache.NeverExpirePolicy[string, int]{})
+
+    cache.Put("k", 1, time.Nanosecond)
+    clock.now = clock.now.Add(24 * time.Hour)
+
+    if _, ok := cache.Get("k"); !ok {
+        t.Fatal("NeverExpirePolicy should keep the item alive regardless of its TTL")
+    }
+}
+
+
+// TestAbsoluteTTLPolicyIgnoresKeepRefreshing guards the policy's documented
+// guarantee: a PutRefreshing item must still expire at its original
+// ExpiresAt, even though repeated Get hits would otherwise slide it forward.
+func TestAbsoluteTTLPolicyIgnoresKeepRefreshing(t *testing.T) {
+    cache := timedcache.New[string, int]()
+    defer cache.Close()
+
+    clock := &fakeClock{now: time.Unix(1000, 0)}
+    cache.SetClock(clock)
+    cache.SetExpirationPolicy(timedcache.AbsoluteTTLPolicy[string, int]{})
+
+    cache.PutRefreshing("k", 1, time.Minute)
+
+    for i := 0; i < 3; i++ {
+        clock.now = clock.now.Add(30 * time.Second)
+        cache.Get("k")
+    }
+
+    clock.now = clock.now.Add(time.Second)
+    if _, ok := cache.Get("k"); ok {
+        t.Fatal("AbsoluteTTLPolicy should expire a KeepRefreshing item at its original ExpiresAt, not slide it forward on hits")
+    }
+}
+
+
+func TestSlidingTTLPolicyExtendsOnlyWhenKeepRefreshing(t *testing.T) {
+    cache := timedcache.New[string, int]()
+    defer cache.Close()
+
+    clock := &fakeClock{now: time.Unix(1000, 0)}
+    cache.SetClock(clock)
+    cache.SetExpirationPolicy(timedcache.SlidingTTLPolicy[string, int]{})
+
+    cache.PutRefreshing("refreshing", 1, time.Minute)
+    cache.Put("fixed", 1, time.Minute)
+
+    clock.now = clock.now.Add(45 * time.Second)
+    if _, ok := cache.Get("refreshing"); !ok {
+        t.Fatal("expected refreshing item to still be present before its sliding window elapses")
+    }
+    if _, ok := cache.Get("fixed"); !ok {
+        t.Fatal("expected fixed item to still be present before its ExpiresAt")
+    }
+
+    clock.now = clock.now.Add(45 * time.Second)
+    if _, ok := cache.Get("refreshing"); !ok {
+        t.Fatal("Get hits should keep sliding a KeepRefreshing item's window forward under SlidingTTLPolicy")
+    }
+    if _, ok := cache.Get("fixed"); ok {
+        t.Fatal("expected fixed item to have expired at its original ExpiresAt")
+    }
+}
+
+
+func TestIdleSinceLastAccessPolicyExpiresAfterMaxIdle(t *testing.T) {
+    cache := timedcache.New[string, int]()
+    defer cache.Close()
+
+    clock := &fakeClock{now: time.Unix(1000, 0)}
+    cache.SetClock(clock)
+    cache.SetExpirationPolicy(timedcache.IdleSinceLastAccessPolicy[string, int]{MaxIdle: time.Minute})
+
+    cache.Put("k", 1, time.Second) // ExpiresAt is ignored under this policy
+
+    clock.now = clock.now.Add(30 * time.Second)
+    if _, ok := cache.Get("k"); !ok {
+        t.Fatal("item accessed within MaxIdle should still be present")
+    }
+
+    clock.now = clock.now.Add(2 * time.Minute)
+    if _, ok := cache.Get("k"); ok {
+        t.Fatal("item idle past MaxIdle should have expired")
+    }
+}
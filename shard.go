@@ -0,0 +1,74 @@
+package timedcache
+
+
+import (
+    "fmt"
+    "sync"
+)
+
+
+// defaultShardCount is used by New, NewWithCustomInterval and NewWithLoader,
+// which don't let the caller pick a shard count directly
+const defaultShardCount = 32
+
+
+// shard is one bucket of the cache's storage: its own map guarded by its
+// own RWMutex, so that reads against different shards never contend
+type shard[K comparable, V any] struct {
+    mu     sync.RWMutex
+    items  map[K]cacheItem[V]
+}
+
+
+func newShards[K comparable, V any](count int) ([]*shard[K, V]) {
+    shards := make([]*shard[K, V], count)
+    for i := range shards {
+        shards[i] = &shard[K, V]{items: make(map[K]cacheItem[V])}
+    }
+    return shards
+}
+
+
+// shardFor picks the shard responsible for key by FNV-1a hashing it
+// String keys, the overwhelmingly common case, are hashed directly; any
+// other comparable type falls back to hashing its default string format,
+// which costs a reflection-driven allocation but keeps shardFor generic
+func (tc *timedCache[K, V]) shardFor(key K) (*shard[K, V]) {
+    var s string
+    if str, ok := any(key).(string); ok {
+        s = str
+    } else {
+        s = fmt.Sprintf("%v", key)
+    }
+    return tc.shards[fnv32a(s)&tc.shardMask]
+}
+
+
+// fnv32a hashes s with the FNV-1a algorithm, computed directly over the
+// string's bytes rather than via hash.Hash32, to avoid an allocation on
+// every single cache operation
+func fnv32a(s string) (uint32) {
+    const (
+        offsetBasis uint32 = 2166136261
+        prime       uint32 = 16777619
+    )
+    h := offsetBasis
+    for i := 0; i < len(s); i++ {
+        h ^= uint32(s[i])
+        h *= prime
+    }
+    return h
+}
+
+
+// nextPowerOfTwo rounds n up to the next power of two, with a floor of 1
+func nextPowerOfTwo(n int) (int) {
+    if n < 1 {
+        return 1
+    }
+    p := 1
+    for p < n {
+        p <<= 1
+    }
+    return p
+}
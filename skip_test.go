@@ -0,0 +1,43 @@
+package timedcache_test
+
+
+import (
+    "testing"
+    "time"
+
+    timedcache "github.com/TomiHiltunen/TimedCache"
+)
+
+
+// TestSetSkipTTLExtensionOnHitStopsGetFromRefreshing exercises the toggle
+// itself, not just Peek's always-no-refresh behavior: with skip=false a
+// KeepRefreshing item's ExpiresAt should keep sliding forward on Get hits,
+// and with skip=true it should stay fixed at its original deadline.
+func TestSetSkipTTLExtensionOnHitStopsGetFromRefreshing(t *testing.T) {
+    cache := timedcache.NewWithCustomInterval[string, int](time.Hour)
+    defer cache.Close()
+
+    clock := &fakeClock{now: time.Unix(1000, 0)}
+    cache.SetClock(clock)
+
+    cache.PutRefreshing("sliding", 1, time.Minute)
+    clock.now = clock.now.Add(45 * time.Second)
+    if _, ok := cache.Get("sliding"); !ok {
+        t.Fatal("expected sliding item to survive a Get hit before its TTL elapses")
+    }
+    clock.now = clock.now.Add(45 * time.Second)
+    if _, ok := cache.Get("sliding"); !ok {
+        t.Fatal("expected the earlier Get hit to have refreshed the TTL, keeping the item alive")
+    }
+
+    cache.SetSkipTTLExtensionOnHit(true)
+    cache.PutRefreshing("fixed", 1, time.Minute)
+    clock.now = clock.now.Add(45 * time.Second)
+    if _, ok := cache.Get("fixed"); !ok {
+        t.Fatal("expected fixed item to survive a Get hit before its TTL elapses")
+    }
+    clock.now = clock.now.Add(45 * time.Second)
+    if _, ok := cache.Get("fixed"); ok {
+        t.Fatal("SetSkipTTLExtensionOnHit(true) should have left the item's original ExpiresAt untouched by Get hits")
+    }
+}
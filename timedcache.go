@@ -13,7 +13,7 @@ package timedcache
 import (
     "time"
     "sync"
-    "errors"
+    "runtime"
 )
 
 
@@ -22,131 +22,322 @@ import (
 // ExpiresAt - after this time the object is automatically removed
 // KeepRefreshing - whether to refresh the expiration time when object is used
 // RefreshBy - the extra duration given in a refresh
-type cacheItem struct {
-    Content         interface{}
+type cacheItem[V any] struct {
+    Content         V
     ExpiresAt       time.Time
     KeepRefreshing  bool
     RefreshBy       time.Duration
+    LastAccessedAt  time.Time
 }
 
 
-// TimedCache is the body for the storage cache
-// items - The stored items
-// mu    - Mutex
-type TimedCache struct {
-    items           map[string]cacheItem
+// isExpired reports whether the item's TTL has passed as of now
+func (ci cacheItem[V]) isExpired(now time.Time) (bool) {
+    return ci.ExpiresAt.Before(now)
+}
+
+
+// timedCache is the body for the storage cache
+// shards - the stored items, split across shards to reduce lock contention
+// mu     - guards the settings below, not the shards themselves
+// janitor - background goroutine that periodically sweeps expired items
+//
+// It is wrapped by the exported TimedCache so that the janitor goroutine,
+// which only ever holds a reference to timedCache, does not keep the
+// publicly held *TimedCache reachable and therefore does not block it
+// from being garbage collected.
+type timedCache[K comparable, V any] struct {
+    shards          []*shard[K, V]
+    shardMask       uint32
     interval        time.Duration
-    mu              sync.Mutex
+    mu              sync.RWMutex
+    loader          func() (V, time.Duration, error)
+    inflightMu      sync.Mutex
+    inflight        map[K]*inflightCall[V]
+    onEvicted       func(key K, value V, reason EvictionReason)
+    skipTTLExtensionOnHit  bool
+    janitor         *janitor
+    closeOnce       sync.Once
+    clock           Clock
+    policy          ExpirationPolicy[K, V]
+}
+
+
+// TimedCache is the body for the storage cache
+type TimedCache[K comparable, V any] struct {
+    *timedCache[K, V]
 }
 
 
 // Creates a new instance of TimedCache
-func New() (TimedCache) {
-    newCache := TimedCache {
-        items: make(map[string]cacheItem),
-        interval: time.Minute,
-    }
-    go newCache.startInterval()
-    return newCache
+func New[K comparable, V any]() (*TimedCache[K, V]) {
+    return NewWithCustomInterval[K, V](time.Minute)
 }
 
 
 // Creates a new instance of TimedCache with a custom interval
 // interval - the custom interval for expiration checking
-func NewWithCustomInterval(interval time.Duration) (TimedCache) {
-    newCache := TimedCache {
-        items: make(map[string]cacheItem),
+func NewWithCustomInterval[K comparable, V any](interval time.Duration) (*TimedCache[K, V]) {
+    return NewWithShards[K, V](defaultShardCount, interval)
+}
+
+
+// Creates a new instance of TimedCache with a custom number of shards
+// shards - rounded up to the next power of two; each shard has its own lock
+// interval - the custom interval for expiration checking
+func NewWithShards[K comparable, V any](shards int, interval time.Duration) (*TimedCache[K, V]) {
+    shardCount := nextPowerOfTwo(shards)
+    inner := &timedCache[K, V] {
+        shards: newShards[K, V](shardCount),
+        shardMask: uint32(shardCount - 1),
         interval: interval,
+        inflight: make(map[K]*inflightCall[V]),
+        clock: realClock{},
     }
-    go newCache.startInterval()
+    startJanitor(inner)
+    wrapper := &TimedCache[K, V]{inner}
+    runtime.SetFinalizer(wrapper, stopJanitorOnFinalize[K, V])
+    return wrapper
+}
+
+
+// Creates a new instance of TimedCache that uses loader as the default
+// loader for GetOrLoad calls that don't supply one of their own
+// loader - called on a cache miss, returning the value, its TTL and an error
+func NewWithLoader[K comparable, V any](loader func() (V, time.Duration, error)) (*TimedCache[K, V]) {
+    newCache := New[K, V]()
+    newCache.loader = loader
     return newCache
 }
 
 
 // Add item without refreshing
-func (tc *TimedCache) Put(key string, content interface{}, duration time.Duration) {
-    tc.mu.Lock()
-    tc.items[key] = cacheItem {
+func (tc *timedCache[K, V]) Put(key K, content V, duration time.Duration) {
+    now := tc.currentClock().Now()
+    sh := tc.shardFor(key)
+    sh.mu.Lock()
+    replaced, hadOld := sh.items[key]
+    sh.items[key] = cacheItem[V] {
         Content: content,
-        ExpiresAt: time.Now().UTC().Add(duration),
+        ExpiresAt: now.Add(duration),
         KeepRefreshing: false,
+        LastAccessedAt: now,
+    }
+    sh.mu.Unlock()
+    if hadOld {
+        if onEvicted := tc.currentOnEvicted(); onEvicted != nil {
+            onEvicted(key, replaced.Content, Replaced)
+        }
     }
-    tc.mu.Unlock()
 }
 
 
 // Add item with refreshing
-func (tc *TimedCache) PutRefreshing(key string, content interface{}, duration time.Duration) {
-    tc.mu.Lock()
-    tc.items[key] = cacheItem {
+func (tc *timedCache[K, V]) PutRefreshing(key K, content V, duration time.Duration) {
+    now := tc.currentClock().Now()
+    sh := tc.shardFor(key)
+    sh.mu.Lock()
+    replaced, hadOld := sh.items[key]
+    sh.items[key] = cacheItem[V] {
         Content: content,
-        ExpiresAt: time.Now().UTC().Add(duration),
+        ExpiresAt: now.Add(duration),
         KeepRefreshing: true,
         RefreshBy: duration,
+        LastAccessedAt: now,
+    }
+    sh.mu.Unlock()
+    if hadOld {
+        if onEvicted := tc.currentOnEvicted(); onEvicted != nil {
+            onEvicted(key, replaced.Content, Replaced)
+        }
     }
-    tc.mu.Unlock()
 }
 
 
 // Delete item
-func (tc *TimedCache) Delete(key string) {
-    tc.mu.Lock()
-    delete(tc.items, key)
-    tc.mu.Unlock()
+func (tc *timedCache[K, V]) Delete(key K) {
+    sh := tc.shardFor(key)
+    sh.mu.Lock()
+    removed, ok := sh.items[key]
+    delete(sh.items, key)
+    sh.mu.Unlock()
+    if ok {
+        if onEvicted := tc.currentOnEvicted(); onEvicted != nil {
+            onEvicted(key, removed.Content, Deleted)
+        }
+    }
 }
 
 
 // Get item
-// If item is automatically refreshable, extends the expiration time
-func (tc *TimedCache) Get(key string) (interface{}, error) {
-    tc.mu.Lock()
-    if theItem, ok := tc.items[key]; ok {
-        if theItem.KeepRefreshing {
-            theItem.ExpiresAt = time.Now().UTC().Add(theItem.RefreshBy)
-            tc.items[key] = theItem
+// Expired items are treated as a miss and removed lazily, even before the
+// janitor's next sweep
+// If item is automatically refreshable, extends the expiration time,
+// unless SetSkipTTLExtensionOnHit(true) has been called on this cache or a
+// custom ExpirationPolicy is installed (the policy then owns hit behavior,
+// e.g. AbsoluteTTLPolicy ignoring KeepRefreshing entirely)
+// Returns the zero value of V and ok=false if the key is not present
+func (tc *timedCache[K, V]) Get(key K) (V, bool) {
+    now := tc.currentClock().Now()
+    sh := tc.shardFor(key)
+    hasPolicy := tc.hasPolicy()
+
+    sh.mu.RLock()
+    theItem, ok := sh.items[key]
+    sh.mu.RUnlock()
+    if !ok {
+        var zero V
+        return zero, false
+    }
+
+    // Only items that need a write (expiry, a sliding-TTL refresh, or a
+    // LastAccessedAt update whenever any custom policy is set) upgrade to the
+    // write lock. The item is re-read under that lock before being mutated,
+    // since a concurrent Put/Delete may have changed it between the RLock
+    // probe above and acquiring the write lock here.
+    if tc.expired(key, theItem, now) || (theItem.KeepRefreshing && !tc.skipRefreshOnHit() && !hasPolicy) || hasPolicy {
+        sh.mu.Lock()
+        theItem, ok = sh.items[key]
+        if !ok {
+            sh.mu.Unlock()
+            var zero V
+            return zero, false
+        }
+        if tc.expired(key, theItem, now) {
+            delete(sh.items, key)
+            sh.mu.Unlock()
+            if onEvicted := tc.currentOnEvicted(); onEvicted != nil {
+                onEvicted(key, theItem.Content, Expired)
+            }
+            var zero V
+            return zero, false
+        }
+        // The built-in sliding-refresh mutation only applies when no custom
+        // policy is installed; a policy decides hit behavior itself (e.g.
+        // SlidingTTLPolicy reads LastAccessedAt below instead of ExpiresAt).
+        if theItem.KeepRefreshing && !tc.skipRefreshOnHit() && !hasPolicy {
+            theItem.ExpiresAt = now.Add(theItem.RefreshBy)
         }
-        tc.mu.Unlock()
-        return theItem.Content, nil
+        theItem.LastAccessedAt = now
+        sh.items[key] = theItem
+        sh.mu.Unlock()
+        return theItem.Content, true
     }
-    tc.mu.Unlock()
-    return nil, errors.New("Item not found")
+
+    return theItem.Content, true
 }
 
 
-// Checks whether a key exists in the repository
-func (tc *TimedCache) KeyExists(key string) (bool) {
-    tc.mu.Lock()
-    if _, ok := tc.items[key]; ok {
-        tc.mu.Unlock()
-        return true
+// Peek returns an item without ever extending its expiration time,
+// regardless of SetSkipTTLExtensionOnHit
+// An already-expired item is treated as a miss, same as Get
+func (tc *timedCache[K, V]) Peek(key K) (V, bool) {
+    sh := tc.shardFor(key)
+    sh.mu.RLock()
+    theItem, ok := sh.items[key]
+    sh.mu.RUnlock()
+    if !ok || tc.expired(key, theItem, tc.currentClock().Now()) {
+        var zero V
+        return zero, false
     }
+    return theItem.Content, true
+}
+
+
+// SetSkipTTLExtensionOnHit toggles whether KeepRefreshing items have their
+// expiration extended on every Get/GetOrLoad hit (the default, sliding-window
+// behavior) or keep their originally assigned expiration (fixed-TTL, DNS-style
+// behavior)
+func (tc *timedCache[K, V]) SetSkipTTLExtensionOnHit(skip bool) {
+    tc.mu.Lock()
+    tc.skipTTLExtensionOnHit = skip
     tc.mu.Unlock()
-    return false
 }
 
 
-// Start interval
-// When triggered will activate removal of expired content
-func (tc *TimedCache) startInterval() {
-    for {
-        time.Sleep(tc.interval)
-        tc.RemoveExpired()
+func (tc *timedCache[K, V]) skipRefreshOnHit() (bool) {
+    tc.mu.RLock()
+    defer tc.mu.RUnlock()
+    return tc.skipTTLExtensionOnHit
+}
+
+
+func (tc *timedCache[K, V]) currentClock() (Clock) {
+    tc.mu.RLock()
+    defer tc.mu.RUnlock()
+    return tc.clock
+}
+
+
+func (tc *timedCache[K, V]) currentOnEvicted() (func(key K, value V, reason EvictionReason)) {
+    tc.mu.RLock()
+    defer tc.mu.RUnlock()
+    return tc.onEvicted
+}
+
+
+// hasPolicy reports whether a custom ExpirationPolicy is set; such policies
+// may depend on LastAccessedAt (e.g. IdleSinceLastAccessPolicy), so Get
+// must keep it up to date even on items with a fixed, non-refreshing TTL
+func (tc *timedCache[K, V]) hasPolicy() (bool) {
+    tc.mu.RLock()
+    defer tc.mu.RUnlock()
+    return tc.policy != nil
+}
+
+
+// Checks whether a key exists in the repository
+// An already-expired item is treated as absent, same as Get
+func (tc *timedCache[K, V]) KeyExists(key K) (bool) {
+    sh := tc.shardFor(key)
+    sh.mu.RLock()
+    theItem, ok := sh.items[key]
+    sh.mu.RUnlock()
+    if !ok {
+        return false
     }
+    return !tc.expired(key, theItem, tc.currentClock().Now())
 }
 
 
 // Remove expired
-func (tc *TimedCache) RemoveExpired() {
-    tc.mu.Lock()
-    timeNow := time.Now().UTC()
-    for key, curItem := range tc.items {
-        if curItem.ExpiresAt.Before(timeNow) {
-            delete(tc.items, key)
+func (tc *timedCache[K, V]) RemoveExpired() {
+    timeNow := tc.currentClock().Now()
+    var expired []cacheItem[V]
+    var expiredKeys []K
+    for _, sh := range tc.shards {
+        sh.mu.Lock()
+        for key, curItem := range sh.items {
+            if tc.expired(key, curItem, timeNow) {
+                delete(sh.items, key)
+                expired = append(expired, curItem)
+                expiredKeys = append(expiredKeys, key)
+            }
+        }
+        sh.mu.Unlock()
+    }
+    onEvicted := tc.currentOnEvicted()
+    if onEvicted != nil {
+        for i, key := range expiredKeys {
+            onEvicted(key, expired[i].Content, Expired)
         }
     }
-    tc.mu.Unlock()
 }
 
 
+// Close stops the background janitor goroutine
+// It is safe to call Close more than once, and it is also safe to never
+// call it at all: the janitor is stopped automatically once the cache
+// becomes unreachable and is garbage collected
+func (tc *timedCache[K, V]) Close() {
+    tc.closeOnce.Do(func() {
+        tc.janitor.stop()
+    })
+}
 
 
+// stopJanitorOnFinalize is installed via runtime.SetFinalizer so the
+// janitor goroutine is stopped even if the caller never calls Close
+func stopJanitorOnFinalize[K comparable, V any](wrapper *TimedCache[K, V]) {
+    wrapper.Close()
+}
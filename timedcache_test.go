@@ -0,0 +1,51 @@
+package timedcache_test
+
+
+import (
+    "testing"
+    "time"
+
+    timedcache "github.com/TomiHiltunen/TimedCache"
+)
+
+
+// fakeClock lets tests control the cache's notion of "now" directly,
+// instead of sleeping on the real clock.
+type fakeClock struct {
+    now  time.Time
+}
+
+
+func (f *fakeClock) Now() (time.Time) {
+    return f.now
+}
+
+
+func TestGetExpiresLazilyBeforeJanitorSweep(t *testing.T) {
+    // A long interval means the janitor won't sweep during the test; the
+    // expiry must come from Get itself, not RemoveExpired.
+    cache := timedcache.NewWithCustomInterval[string, int](time.Hour)
+    defer cache.Close()
+
+    clock := &fakeClock{now: time.Unix(1000, 0)}
+    cache.SetClock(clock)
+
+    cache.Put("k", 1, time.Second)
+    clock.now = clock.now.Add(2 * time.Second)
+
+    if _, ok := cache.Get("k"); ok {
+        t.Fatal("expected expired item to be a miss even though the janitor hasn't run")
+    }
+    // Rewind the clock so that Peek would still see the item had Get left it in place.
+    clock.now = time.Unix(1000, 0)
+    if _, ok := cache.Peek("k"); ok {
+        t.Fatal("expired item should have been lazily removed by Get")
+    }
+}
+
+
+func TestCloseIsIdempotent(t *testing.T) {
+    cache := timedcache.New[string, int]()
+    cache.Close()
+    cache.Close() // must not panic
+}